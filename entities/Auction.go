@@ -0,0 +1,209 @@
+// Package entities contains the core structures and methods for the order book.
+package entities
+
+// Import standard and external packages.
+import (
+	"sort" // Package for sorting slices.
+	"time" // Package for time-related functions.
+
+	"github.com/google/uuid"                                  // Package to generate unique identifiers (UUIDs) for orders.
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Importing the utils package which contains the LimitOrder definition.
+	"github.com/shopspring/decimal"                           // Package for high-precision decimal arithmetic.
+)
+
+// AuctionFill records a single trade executed during a uniform clearing
+// price auction.
+type AuctionFill struct {
+	BuyOrderID  uuid.UUID       // ID of the bid order that participated in this fill.
+	SellOrderID uuid.UUID       // ID of the ask order that participated in this fill.
+	Price       decimal.Decimal // The uniform clearing price the fill executed at.
+	Quantity    int             // Number of shares matched in this fill.
+}
+
+// AuctionReport summarizes the outcome of a single call-auction match,
+// including the clearing price that was found and every fill it produced.
+type AuctionReport struct {
+	ClearingPrice decimal.Decimal // The price P* that maximized executable volume.
+	Fills         []AuctionFill   // Fills executed at ClearingPrice, in time priority order.
+}
+
+// MatchAuction runs a single uniform clearing price ("call") auction over
+// the resting bids and asks, distinct from the sequential price-time
+// matching performed by Match(). It is intended for opening/closing
+// auctions rather than continuous trading.
+//
+// The clearing price P* is chosen by building, over the sorted union of
+// distinct resting prices, the cumulative demand D(p) (bid quantity at
+// Price >= p) and cumulative supply S(p) (ask quantity at Price <= p), then
+// picking the price that maximizes the executable volume min(D(p), S(p)).
+// Ties are broken by preferring the price closest to the midpoint of the
+// tying price range. If the two sides are imbalanced at P*, the shorter
+// side fills in full and the longer side is allocated by time priority
+// using LimitOrder.Timestamp.
+func (ob *OrderBook) MatchAuction() AuctionReport {
+	report := AuctionReport{}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	// An auction needs resting interest on both sides to produce any fills.
+	if ob.Bids.Len() == 0 || ob.Asks.Len() == 0 {
+		return report
+	}
+
+	bids := ob.Bids.Orders() // Best (highest) price first.
+	asks := ob.Asks.Orders() // Best (lowest) price first.
+
+	// Collect the distinct candidate clearing prices present on either side.
+	priceSet := make(map[string]decimal.Decimal)
+	for _, bid := range bids {
+		priceSet[bid.Price.String()] = bid.Price
+	}
+	for _, ask := range asks {
+		priceSet[ask.Price.String()] = ask.Price
+	}
+
+	prices := make([]decimal.Decimal, 0, len(priceSet))
+	for _, price := range priceSet {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LessThan(prices[j]) })
+
+	// Find the candidate price, or tied range of prices, that maximizes the
+	// executable volume min(D(p), S(p)).
+	bestVolume := -1
+	tieStart, tieEnd := 0, 0
+	for i, price := range prices {
+		volume := intMin(cumulativeDemand(bids, price), cumulativeSupply(asks, price))
+		if volume > bestVolume {
+			bestVolume = volume
+			tieStart, tieEnd = i, i
+		} else if volume == bestVolume && volume > 0 {
+			tieEnd = i
+		}
+	}
+
+	// No crossing price exists; nothing to do.
+	if bestVolume <= 0 {
+		return report
+	}
+
+	// Break ties by preferring the price closest to the midpoint of the tied range.
+	midpoint := prices[tieStart].Add(prices[tieEnd]).Div(decimal.NewFromInt(2))
+	clearingPrice := prices[tieStart]
+	bestDistance := clearingPrice.Sub(midpoint).Abs()
+	for i := tieStart + 1; i <= tieEnd; i++ {
+		distance := prices[i].Sub(midpoint).Abs()
+		if distance.LessThan(bestDistance) {
+			clearingPrice = prices[i]
+			bestDistance = distance
+		}
+	}
+	report.ClearingPrice = clearingPrice
+
+	// Gather the orders that qualify to trade at the clearing price, in time
+	// priority order, so that any imbalance between the two sides is
+	// allocated FIFO rather than arbitrarily.
+	var qualifiedBids, qualifiedAsks []*utils.LimitOrder
+	for _, bid := range bids {
+		if bid.Price.GreaterThanOrEqual(clearingPrice) {
+			qualifiedBids = append(qualifiedBids, bid)
+		}
+	}
+	for _, ask := range asks {
+		if ask.Price.LessThanOrEqual(clearingPrice) {
+			qualifiedAsks = append(qualifiedAsks, ask)
+		}
+	}
+	sort.Slice(qualifiedBids, func(i, j int) bool { return qualifiedBids[i].Timestamp.Before(qualifiedBids[j].Timestamp) })
+	sort.Slice(qualifiedAsks, func(i, j int) bool { return qualifiedAsks[i].Timestamp.Before(qualifiedAsks[j].Timestamp) })
+
+	// Cross the two queues at the clearing price until the executable
+	// volume is exhausted, filling strictly in time priority order.
+	bidIdx, askIdx, remaining := 0, 0, bestVolume
+	for remaining > 0 && bidIdx < len(qualifiedBids) && askIdx < len(qualifiedAsks) {
+		bid := qualifiedBids[bidIdx]
+		ask := qualifiedAsks[askIdx]
+
+		quantityFilled := intMin(intMin(bid.Quantity, ask.Quantity), remaining)
+		if quantityFilled <= 0 {
+			break
+		}
+
+		report.Fills = append(report.Fills, AuctionFill{
+			BuyOrderID:  bid.ID,
+			SellOrderID: ask.ID,
+			Price:       clearingPrice,
+			Quantity:    quantityFilled,
+		})
+
+		// Publish and feed the breaker exactly as Match does, so subscribers
+		// (e.g. twap.Execution) and an installed CircuitBreaker see auction
+		// fills too, rather than being blind to this matching path.
+		trade := Trade{
+			Ticker:      ob.ticker,
+			BuyOrderID:  bid.ID,
+			SellOrderID: ask.ID,
+			Price:       clearingPrice,
+			Quantity:    quantityFilled,
+			Timestamp:   time.Now(),
+		}
+		ob.publish(trade)
+		if ob.breaker != nil {
+			ob.breaker.recordTrade(trade)
+		}
+
+		// Go through the ladders so their level and total quantity counters
+		// stay in step with the orders' own quantities.
+		ob.Bids.SetQuantity(bid.ID, bid.Quantity-quantityFilled)
+		ob.Asks.SetQuantity(ask.ID, ask.Quantity-quantityFilled)
+		remaining -= quantityFilled
+
+		if bid.Quantity == 0 {
+			bidIdx++
+		}
+		if ask.Quantity == 0 {
+			askIdx++
+		}
+	}
+
+	// bids and asks hold the live *LimitOrder pointers resting in the
+	// ladders, and SetQuantity above has already applied the decrements to
+	// the book; just drop whatever ended up fully filled.
+	for _, bid := range bids {
+		if bid.Quantity == 0 {
+			ob.Bids.Remove(bid.ID)
+		}
+	}
+	for _, ask := range asks {
+		if ask.Quantity == 0 {
+			ob.Asks.Remove(ask.ID)
+		}
+	}
+
+	return report
+}
+
+// cumulativeDemand returns D(p): the total bid quantity in bids resting at
+// prices greater than or equal to p.
+func cumulativeDemand(bids []*utils.LimitOrder, price decimal.Decimal) int {
+	total := 0
+	for _, bid := range bids {
+		if bid.Price.GreaterThanOrEqual(price) {
+			total += bid.Quantity
+		}
+	}
+	return total
+}
+
+// cumulativeSupply returns S(p): the total ask quantity in asks resting at
+// prices less than or equal to p.
+func cumulativeSupply(asks []*utils.LimitOrder, price decimal.Decimal) int {
+	total := 0
+	for _, ask := range asks {
+		if ask.Price.LessThanOrEqual(price) {
+			total += ask.Quantity
+		}
+	}
+	return total
+}
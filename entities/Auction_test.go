@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMatchAuctionClearingPrice(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	// Executable volume min(D(p), S(p)) peaks uniquely at 11.00: 15 shares
+	// can clear there, versus 10 at both 10.00 and 12.00.
+	mustAdd(t, ob, "12.00", 10, true)
+	mustAdd(t, ob, "11.00", 5, true)
+	mustAdd(t, ob, "11.00", 5, false)
+	mustAdd(t, ob, "10.00", 10, false)
+
+	report := ob.MatchAuction()
+
+	if !report.ClearingPrice.Equal(mustDecimal(t, "11.00")) {
+		t.Fatalf("expected clearing price 11.00, got %s", report.ClearingPrice)
+	}
+
+	filled := 0
+	for _, fill := range report.Fills {
+		if !fill.Price.Equal(mustDecimal(t, "11.00")) {
+			t.Fatalf("expected every fill at the clearing price, got %s", fill.Price)
+		}
+		filled += fill.Quantity
+	}
+	if filled != 15 {
+		t.Fatalf("expected 15 shares to clear, got %d", filled)
+	}
+}
+
+func TestMatchAuctionNoCrossingPrice(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	mustAdd(t, ob, "9.00", 5, true) // Best bid below best ask: no crossing price.
+	mustAdd(t, ob, "10.00", 5, false)
+
+	report := ob.MatchAuction()
+
+	if len(report.Fills) != 0 {
+		t.Fatalf("expected no fills when bids and asks do not cross, got %d", len(report.Fills))
+	}
+}
+
+func TestMatchAuctionPublishesTrades(t *testing.T) {
+	ob := NewOrderBook("TEST")
+	mustAdd(t, ob, "10.00", 5, true)
+	mustAdd(t, ob, "10.00", 5, false)
+
+	trades := make(chan Trade, 4)
+	unsubscribe := ob.Subscribe(trades)
+	defer unsubscribe()
+
+	report := ob.MatchAuction()
+	if len(report.Fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(report.Fills))
+	}
+
+	select {
+	case trade := <-trades:
+		if trade.Quantity != 5 || !trade.Price.Equal(report.ClearingPrice) {
+			t.Fatalf("published trade %+v does not match auction fill", trade)
+		}
+	default:
+		t.Fatal("expected MatchAuction to publish a Trade for its fill")
+	}
+}
+
+func mustAdd(t *testing.T, ob *OrderBook, price string, quantity int, isBid bool) {
+	t.Helper()
+	if _, err := ob.AddLimitOrder(ob.Ticker(), price, quantity, isBid); err != nil {
+		t.Fatalf("AddLimitOrder(%s, %d, %t) failed: %v", price, quantity, isBid, err)
+	}
+}
+
+func mustDecimal(t *testing.T, value string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		t.Fatalf("invalid decimal %q: %v", value, err)
+	}
+	return d
+}
@@ -0,0 +1,91 @@
+// Package entities contains the core structures and methods for the order book.
+package entities
+
+// Import standard and external packages.
+import (
+	"fmt"  // Package for formatted I/O and error construction.
+	"time" // Package for time-related functions.
+
+	"github.com/google/uuid"                                  // Package to generate unique identifiers (UUIDs) for orders.
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Package containing the LimitOrder definition.
+)
+
+// LimitOrderRequest describes a single order to place via BatchPlaceOrders,
+// mirroring the parameters AddLimitOrder accepts individually.
+type LimitOrderRequest struct {
+	Ticker      string // Ticker symbol the order is for.
+	PriceString string // Order price, as a decimal string.
+	Quantity    int    // Number of shares (or units) to order.
+	IsBid       bool   // true for a bid (buy) order, false for an ask (sell) order.
+}
+
+// RetryPolicy configures how BatchPlaceOrders retries an order that fails a
+// transient rejection (e.g. an installed CircuitBreaker tripping briefly)
+// before giving up on it.
+type RetryPolicy struct {
+	MaxAttempts int           // Maximum attempts per order, including the first. Zero or one disables retrying.
+	Backoff     time.Duration // Delay between attempts.
+}
+
+// BatchPlaceOrders places every request against the book while holding its
+// lock for the whole batch, rather than once per order, substantially
+// cutting lock contention versus calling AddLimitOrder in a loop. The one
+// exception is retry backoff: the lock is released for the duration of
+// time.Sleep(retry.Backoff) so a single retrying order cannot stall
+// unrelated activity on the book for the backoff period, then re-acquired
+// before the batch continues. Results are positional: orders[i]/errs[i]
+// correspond to requests[i]. A request that keeps failing is attempted up to
+// retry.MaxAttempts times in all, the first attempt included, before its
+// slot is left nil with the last error recorded.
+func (ob *OrderBook) BatchPlaceOrders(requests []LimitOrderRequest, retry RetryPolicy) ([]*utils.LimitOrder, []error) {
+	orders := make([]*utils.LimitOrder, len(requests))
+	errs := make([]error, len(requests))
+
+	attempts := retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	for i, request := range requests {
+		var order *utils.LimitOrder
+		var err error
+
+		for attempt := 0; attempt < attempts; attempt++ {
+			order, err = ob.addLimitOrderLocked(request.Ticker, request.PriceString, request.Quantity, request.IsBid)
+			if err == nil {
+				break
+			}
+			if attempt < attempts-1 && retry.Backoff > 0 {
+				ob.mu.Unlock()
+				time.Sleep(retry.Backoff)
+				ob.mu.Lock()
+			}
+		}
+
+		orders[i] = order
+		errs[i] = err
+	}
+
+	return orders, errs
+}
+
+// BatchCancelOrders cancels every id against the book while holding its
+// lock exactly once. Results are positional: errs[i] corresponds to ids[i]
+// and is non-nil if that order was not found.
+func (ob *OrderBook) BatchCancelOrders(ids []uuid.UUID) []error {
+	errs := make([]error, len(ids))
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	for i, id := range ids {
+		if !ob.cancelLocked(id) {
+			errs[i] = fmt.Errorf("entities: order %s not found", id)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils"
+)
+
+func TestBatchPlaceOrdersPartialFailureAndRetryExhaustion(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	requests := []LimitOrderRequest{
+		{Ticker: "TEST", PriceString: "10.00", Quantity: 5, IsBid: true},
+		{Ticker: "TEST", PriceString: "not-a-price", Quantity: 5, IsBid: true},
+	}
+	retry := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	orders, errs := ob.BatchPlaceOrders(requests, retry)
+
+	if orders[0] == nil || errs[0] != nil {
+		t.Fatalf("expected request 0 to succeed, got order=%v err=%v", orders[0], errs[0])
+	}
+	if orders[1] != nil || errs[1] == nil {
+		t.Fatal("expected request 1 to fail after exhausting retries")
+	}
+	if ob.Bids.Len() != 1 {
+		t.Fatalf("expected exactly the one successful order resting on the book, got %d", ob.Bids.Len())
+	}
+}
+
+// TestBatchPlaceOrdersHoldsLockForWholeBatch polls the book's lock while a
+// retry-free batch runs and checks that it is seen held at least once. It
+// does not detect the lock being released between orders, and it is
+// skipped if the polling never catches the lock held.
+func TestBatchPlaceOrdersHoldsLockForWholeBatch(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	requests := make([]LimitOrderRequest, 2000)
+	for i := range requests {
+		requests[i] = LimitOrderRequest{Ticker: "TEST", PriceString: "10.00", Quantity: 1, IsBid: true}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ob.BatchPlaceOrders(requests, RetryPolicy{})
+	}()
+
+	sawLocked := false
+poll:
+	for {
+		select {
+		case <-done:
+			break poll
+		default:
+			if ob.mu.TryLock() {
+				ob.mu.Unlock()
+			} else {
+				sawLocked = true
+			}
+		}
+	}
+
+	if !sawLocked {
+		t.Skip("never observed the book locked mid-batch; timing too coarse on this machine to exercise the check")
+	}
+}
+
+// TestBatchPlaceOrdersReleasesLockDuringBackoff checks the flip side: the
+// lock must not be held across retry.Backoff, so unrelated calls aren't
+// stalled for the backoff duration.
+func TestBatchPlaceOrdersReleasesLockDuringBackoff(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	requests := []LimitOrderRequest{
+		{Ticker: "TEST", PriceString: "not-a-price", Quantity: 1, IsBid: true},
+	}
+	retry := RetryPolicy{MaxAttempts: 2, Backoff: 40 * time.Millisecond}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ob.BatchPlaceOrders(requests, retry)
+	}()
+
+	// Give the batch time to fail its first attempt and enter backoff.
+	time.Sleep(10 * time.Millisecond)
+
+	unlocked := make(chan struct{})
+	go func() {
+		ob.mu.Lock()
+		ob.mu.Unlock()
+		close(unlocked)
+	}()
+
+	select {
+	case <-unlocked:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("expected the book lock to become available during BatchPlaceOrders' backoff sleep")
+	}
+
+	<-done
+}
+
+func TestBatchCancelOrdersPartialFailure(t *testing.T) {
+	ob := NewOrderBook("TEST")
+	order := &utils.LimitOrder{ID: uuid.New(), Ticker: "TEST", Price: mustDecimal(t, "10.00"), Quantity: 5, IsBid: true}
+	if _, err := ob.AddOrder(order); err != nil {
+		t.Fatalf("AddOrder failed: %v", err)
+	}
+
+	missing := uuid.New()
+	errs := ob.BatchCancelOrders([]uuid.UUID{order.ID, missing})
+
+	if errs[0] != nil {
+		t.Fatalf("expected cancelling a resting order to succeed, got %v", errs[0])
+	}
+	if errs[1] == nil {
+		t.Fatal("expected cancelling a missing order to report an error")
+	}
+	if ob.Bids.Len() != 0 {
+		t.Fatalf("expected the book to be empty after cancelling its only order, got %d", ob.Bids.Len())
+	}
+}
@@ -0,0 +1,161 @@
+// Package entities contains the core structures and methods for the order book.
+package entities
+
+// Import standard and external packages.
+import (
+	"fmt"  // Package for formatted I/O and error construction.
+	"sync" // Package providing the mutex guarding the breaker's internal state.
+	"time" // Package for time-related functions.
+
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Package containing the LimitOrder definition.
+	"github.com/shopspring/decimal"                           // Package for high-precision decimal arithmetic.
+)
+
+// CircuitBreakerError is returned by AddLimitOrder and AddOrder when an
+// installed CircuitBreaker rejects the incoming order.
+type CircuitBreakerError struct {
+	Reason string // Human-readable explanation of which risk control tripped.
+}
+
+// Error implements the error interface for CircuitBreakerError.
+func (e *CircuitBreakerError) Error() string {
+	return fmt.Sprintf("entities: order rejected by circuit breaker: %s", e.Reason)
+}
+
+// lossEvent records the notional size of one adverse trade, used to compute
+// cumulative loss over a rolling window.
+type lossEvent struct {
+	at     time.Time
+	amount decimal.Decimal
+}
+
+// CircuitBreaker holds the risk thresholds an OrderBook checks incoming
+// orders and recent trades against before allowing an order to rest on the
+// book, installed via OrderBook.SetCircuitBreaker. A trade is considered a
+// "loss" when it prints below the previous trade's price, and its notional
+// size is Price delta * Quantity; this is a simple adverse-price-move proxy
+// rather than a position-aware P&L calculation.
+type CircuitBreaker struct {
+	MaxConsecutiveLosses int             // Reject new orders once this many losing trades have printed in a row. Zero disables the check.
+	MaxLossPerRound      decimal.Decimal // Reject new orders once a single adverse trade's notional exceeds this. Zero disables the check.
+	MaxCumulativeLoss    decimal.Decimal // Reject new orders once cumulative loss notional within LossWindow exceeds this. Zero disables the check.
+	LossWindow           time.Duration   // Rolling window cumulative loss is measured over.
+	MaxOrderNotional     decimal.Decimal // Reject any single order whose Price * Quantity exceeds this. Zero disables the check.
+	PriceBand            decimal.Decimal // Reject an order priced more than this far from the last trade price. Zero disables the check.
+
+	mu                sync.Mutex
+	consecutiveLosses int
+	losses            []lossEvent // Loss events within LossWindow, oldest first.
+	lastTradePrice    decimal.Decimal
+	haveLastTrade     bool
+	tripped           bool // Set once a single trade's loss exceeds MaxLossPerRound; rejects every order until Reset.
+}
+
+// Reset clears a breaker's tripped state and loss streak, allowing orders
+// to flow again after an operator has reviewed a halt triggered by
+// MaxLossPerRound.
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.tripped = false
+	cb.consecutiveLosses = 0
+	cb.losses = nil
+}
+
+// check inspects order against the breaker's configured thresholds and the
+// match history recorded so far, returning a *CircuitBreakerError if the
+// order should be rejected.
+func (cb *CircuitBreaker) check(order *utils.LimitOrder) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.tripped {
+		return &CircuitBreakerError{Reason: "breaker is tripped after a single-round loss exceeding MaxLossPerRound; call Reset to resume"}
+	}
+
+	if !cb.MaxOrderNotional.IsZero() {
+		notional := order.Price.Mul(decimal.NewFromInt(int64(order.Quantity)))
+		if notional.GreaterThan(cb.MaxOrderNotional) {
+			return &CircuitBreakerError{Reason: fmt.Sprintf("order notional %s exceeds max order notional %s", notional, cb.MaxOrderNotional)}
+		}
+	}
+
+	if !cb.PriceBand.IsZero() && cb.haveLastTrade {
+		deviation := order.Price.Sub(cb.lastTradePrice).Abs()
+		if deviation.GreaterThan(cb.PriceBand) {
+			return &CircuitBreakerError{Reason: fmt.Sprintf("price %s is outside the %s price band around the last trade price %s", order.Price, cb.PriceBand, cb.lastTradePrice)}
+		}
+	}
+
+	if cb.MaxConsecutiveLosses > 0 && cb.consecutiveLosses >= cb.MaxConsecutiveLosses {
+		return &CircuitBreakerError{Reason: fmt.Sprintf("%d consecutive losing trades reached the limit of %d", cb.consecutiveLosses, cb.MaxConsecutiveLosses)}
+	}
+
+	if !cb.MaxCumulativeLoss.IsZero() {
+		cb.pruneLocked(time.Now())
+
+		total := decimal.Zero
+		for _, loss := range cb.losses {
+			total = total.Add(loss.amount)
+		}
+
+		if total.GreaterThan(cb.MaxCumulativeLoss) {
+			return &CircuitBreakerError{Reason: fmt.Sprintf("cumulative loss %s over the last %s exceeds max cumulative loss %s", total, cb.LossWindow, cb.MaxCumulativeLoss)}
+		}
+	}
+
+	return nil
+}
+
+// recordTrade updates the breaker's match history with a trade that just
+// executed, tracking the consecutive loss streak and the rolling window of
+// loss notionals used by check.
+func (cb *CircuitBreaker) recordTrade(trade Trade) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.haveLastTrade && trade.Price.LessThan(cb.lastTradePrice) {
+		loss := cb.lastTradePrice.Sub(trade.Price).Mul(decimal.NewFromInt(int64(trade.Quantity)))
+		cb.consecutiveLosses++
+		cb.losses = append(cb.losses, lossEvent{at: trade.Timestamp, amount: loss})
+
+		if !cb.MaxLossPerRound.IsZero() && loss.GreaterThan(cb.MaxLossPerRound) {
+			cb.tripped = true
+		}
+	} else {
+		cb.consecutiveLosses = 0
+	}
+
+	cb.lastTradePrice = trade.Price
+	cb.haveLastTrade = true
+
+	cb.pruneLocked(trade.Timestamp)
+}
+
+// pruneLocked drops loss events that have aged out of LossWindow. Callers
+// must hold cb.mu.
+func (cb *CircuitBreaker) pruneLocked(now time.Time) {
+	if cb.LossWindow <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-cb.LossWindow)
+	i := 0
+	for ; i < len(cb.losses); i++ {
+		if cb.losses[i].at.After(cutoff) {
+			break
+		}
+	}
+	cb.losses = cb.losses[i:]
+}
+
+// SetCircuitBreaker installs cb as the book's risk control, checked by
+// AddLimitOrder and AddOrder before each new order is allowed to rest on
+// the book. Pass nil to remove any currently installed breaker.
+func (ob *OrderBook) SetCircuitBreaker(cb *CircuitBreaker) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.breaker = cb
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils"
+)
+
+func testOrder(t *testing.T, price string, quantity int) *utils.LimitOrder {
+	t.Helper()
+	return &utils.LimitOrder{
+		Ticker:   "TEST",
+		Price:    mustDecimal(t, price),
+		Quantity: quantity,
+	}
+}
+
+func TestCircuitBreakerMaxOrderNotional(t *testing.T) {
+	cb := &CircuitBreaker{MaxOrderNotional: mustDecimal(t, "1000")}
+
+	if err := cb.check(testOrder(t, "10.00", 50)); err != nil {
+		t.Fatalf("expected order within notional limit to pass, got %v", err)
+	}
+
+	err := cb.check(testOrder(t, "10.00", 200))
+	var cbErr *CircuitBreakerError
+	if !errors.As(err, &cbErr) {
+		t.Fatalf("expected order over notional limit to be rejected, got %v", err)
+	}
+}
+
+func TestCircuitBreakerPriceBand(t *testing.T) {
+	cb := &CircuitBreaker{PriceBand: mustDecimal(t, "1.00")}
+	cb.recordTrade(Trade{Price: mustDecimal(t, "10.00"), Timestamp: time.Now()})
+
+	if err := cb.check(testOrder(t, "10.50", 1)); err != nil {
+		t.Fatalf("expected order within price band to pass, got %v", err)
+	}
+	if err := cb.check(testOrder(t, "12.00", 1)); err == nil {
+		t.Fatal("expected order outside price band to be rejected")
+	}
+}
+
+func TestCircuitBreakerMaxConsecutiveLosses(t *testing.T) {
+	cb := &CircuitBreaker{MaxConsecutiveLosses: 2}
+
+	now := time.Now()
+	cb.recordTrade(Trade{Price: mustDecimal(t, "10.00"), Timestamp: now})
+	cb.recordTrade(Trade{Price: mustDecimal(t, "9.00"), Timestamp: now}) // Loss 1.
+	if err := cb.check(testOrder(t, "9.00", 1)); err != nil {
+		t.Fatalf("expected order to pass after 1 consecutive loss, got %v", err)
+	}
+
+	cb.recordTrade(Trade{Price: mustDecimal(t, "8.00"), Timestamp: now}) // Loss 2.
+	if err := cb.check(testOrder(t, "8.00", 1)); err == nil {
+		t.Fatal("expected order to be rejected after MaxConsecutiveLosses losses in a row")
+	}
+
+	// A trade that is not a loss (price did not drop) resets the streak.
+	cb.recordTrade(Trade{Price: mustDecimal(t, "9.00"), Timestamp: now})
+	if err := cb.check(testOrder(t, "9.00", 1)); err != nil {
+		t.Fatalf("expected consecutive loss streak to reset, got %v", err)
+	}
+}
+
+func TestCircuitBreakerMaxLossPerRoundTrips(t *testing.T) {
+	cb := &CircuitBreaker{MaxLossPerRound: mustDecimal(t, "50")}
+
+	now := time.Now()
+	cb.recordTrade(Trade{Price: mustDecimal(t, "10.00"), Timestamp: now})
+	// Loss of (10.00 - 8.00) * 30 = 60, over the MaxLossPerRound of 50.
+	cb.recordTrade(Trade{Price: mustDecimal(t, "8.00"), Quantity: 30, Timestamp: now})
+
+	if err := cb.check(testOrder(t, "8.00", 1)); err == nil {
+		t.Fatal("expected breaker to be tripped after a single-round loss over MaxLossPerRound")
+	}
+
+	cb.Reset()
+	if err := cb.check(testOrder(t, "8.00", 1)); err != nil {
+		t.Fatalf("expected Reset to clear the tripped state, got %v", err)
+	}
+}
+
+func TestCircuitBreakerMaxCumulativeLoss(t *testing.T) {
+	cb := &CircuitBreaker{MaxCumulativeLoss: mustDecimal(t, "15"), LossWindow: time.Minute}
+
+	now := time.Now()
+	cb.recordTrade(Trade{Price: mustDecimal(t, "10.00"), Timestamp: now})
+	cb.recordTrade(Trade{Price: mustDecimal(t, "9.00"), Quantity: 1, Timestamp: now}) // Loss of 1, total 1.
+	if err := cb.check(testOrder(t, "9.00", 1)); err != nil {
+		t.Fatalf("expected order to pass under cumulative loss limit, got %v", err)
+	}
+
+	cb.recordTrade(Trade{Price: mustDecimal(t, "1.00"), Quantity: 20, Timestamp: now}) // Loss of 160, total far over 15.
+	if err := cb.check(testOrder(t, "1.00", 1)); err == nil {
+		t.Fatal("expected order to be rejected once cumulative loss exceeds MaxCumulativeLoss")
+	}
+}
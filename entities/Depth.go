@@ -0,0 +1,78 @@
+// Package entities contains the core structures and methods for the order book.
+package entities
+
+// Import standard and external packages.
+import (
+	"errors" // Package for constructing sentinel errors.
+
+	"github.com/manuelinfosec/limit-orderbook-go/types"       // Package containing the Depth type describing how far to walk the book.
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Package containing the LimitOrder definition.
+	"github.com/shopspring/decimal"                           // Package for high-precision decimal arithmetic.
+)
+
+// ErrInsufficientDepth is returned by DepthPrice when the book does not
+// have enough resting quantity, within the requested depth, to fill the
+// requested quantity.
+var ErrInsufficientDepth = errors.New("entities: insufficient depth to fill requested quantity")
+
+// DepthPrice returns the volume-weighted average price required to fill
+// quantity shares against the side of the book opposite side (true to price
+// a buy against the resting asks, false to price a sell against the
+// resting bids), along with the quantity actually priced. level bounds how
+// much of the book is walked: types.Top considers only the best price,
+// types.WithLevels walks up to that many distinct price levels, and
+// types.Full walks the entire side.
+//
+// If the requested quantity cannot be fully priced within level, DepthPrice
+// returns the average price and quantity it could price along with
+// ErrInsufficientDepth.
+func (ob *OrderBook) DepthPrice(side bool, quantity int, level types.Depth) (decimal.Decimal, int, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	var orders []*utils.LimitOrder
+	if side {
+		orders = ob.Asks.Orders()
+	} else {
+		orders = ob.Bids.Orders()
+	}
+
+	totalCost := decimal.Zero
+	filled := 0
+	levelsSeen := 0
+	var lastPrice decimal.Decimal
+
+	for _, order := range orders {
+		if filled >= quantity {
+			break
+		}
+
+		newLevel := levelsSeen == 0 || !order.Price.Equal(lastPrice)
+		if newLevel {
+			if level.Kind == types.TopOfBook && levelsSeen >= 1 {
+				break
+			}
+			if level.Kind == types.NLevels && levelsSeen >= level.Levels {
+				break
+			}
+			levelsSeen++
+			lastPrice = order.Price
+		}
+
+		take := intMin(order.Quantity, quantity-filled)
+		totalCost = totalCost.Add(order.Price.Mul(decimal.NewFromInt(int64(take))))
+		filled += take
+	}
+
+	if filled == 0 {
+		return decimal.Zero, 0, ErrInsufficientDepth
+	}
+
+	avgPrice := totalCost.Div(decimal.NewFromInt(int64(filled)))
+
+	if filled < quantity {
+		return avgPrice, filled, ErrInsufficientDepth
+	}
+
+	return avgPrice, filled, nil
+}
@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manuelinfosec/limit-orderbook-go/types"
+)
+
+func TestDepthPriceTopOfBook(t *testing.T) {
+	ob := NewOrderBook("TEST")
+	mustAdd(t, ob, "10.00", 5, false)
+	mustAdd(t, ob, "11.00", 5, false)
+
+	avg, filled, err := ob.DepthPrice(true, 3, types.Top)
+	if err != nil {
+		t.Fatalf("expected no error pricing within the top level, got %v", err)
+	}
+	if filled != 3 {
+		t.Fatalf("expected filled 3, got %d", filled)
+	}
+	if !avg.Equal(mustDecimal(t, "10.00")) {
+		t.Fatalf("expected avg price 10.00, got %s", avg)
+	}
+
+	// Requesting more than the top level holds should stop there, even
+	// though a deeper level could have filled it.
+	avg, filled, err = ob.DepthPrice(true, 10, types.Top)
+	if !errors.Is(err, ErrInsufficientDepth) {
+		t.Fatalf("expected ErrInsufficientDepth, got %v", err)
+	}
+	if filled != 5 {
+		t.Fatalf("expected filled 5 at the top level, got %d", filled)
+	}
+	if !avg.Equal(mustDecimal(t, "10.00")) {
+		t.Fatalf("expected avg price 10.00, got %s", avg)
+	}
+}
+
+func TestDepthPriceNLevels(t *testing.T) {
+	ob := NewOrderBook("TEST")
+	mustAdd(t, ob, "10.00", 5, false)
+	mustAdd(t, ob, "11.00", 5, false)
+	mustAdd(t, ob, "12.00", 5, false)
+
+	avg, filled, err := ob.DepthPrice(true, 10, types.WithLevels(2))
+	if err != nil {
+		t.Fatalf("expected no error filling exactly what 2 levels hold, got %v", err)
+	}
+	if filled != 10 {
+		t.Fatalf("expected filled 10, got %d", filled)
+	}
+	if !avg.Equal(mustDecimal(t, "10.50")) {
+		t.Fatalf("expected avg price 10.50, got %s", avg)
+	}
+
+	_, filled, err = ob.DepthPrice(true, 12, types.WithLevels(2))
+	if !errors.Is(err, ErrInsufficientDepth) {
+		t.Fatalf("expected ErrInsufficientDepth walking beyond 2 levels, got %v", err)
+	}
+	if filled != 10 {
+		t.Fatalf("expected filled capped at 10 across 2 levels, got %d", filled)
+	}
+}
+
+func TestDepthPriceFull(t *testing.T) {
+	ob := NewOrderBook("TEST")
+	mustAdd(t, ob, "10.00", 5, false)
+	mustAdd(t, ob, "11.00", 5, false)
+	mustAdd(t, ob, "12.00", 5, false)
+
+	avg, filled, err := ob.DepthPrice(true, 15, types.Full)
+	if err != nil {
+		t.Fatalf("expected no error filling exactly what the full side holds, got %v", err)
+	}
+	if filled != 15 {
+		t.Fatalf("expected filled 15, got %d", filled)
+	}
+	if !avg.Equal(mustDecimal(t, "11.00")) {
+		t.Fatalf("expected avg price 11.00, got %s", avg)
+	}
+
+	_, filled, err = ob.DepthPrice(true, 20, types.Full)
+	if !errors.Is(err, ErrInsufficientDepth) {
+		t.Fatalf("expected ErrInsufficientDepth when the side can't cover the request, got %v", err)
+	}
+	if filled != 15 {
+		t.Fatalf("expected filled capped at 15, got %d", filled)
+	}
+}
+
+func TestDepthPriceEmptySide(t *testing.T) {
+	ob := NewOrderBook("TEST")
+
+	_, filled, err := ob.DepthPrice(true, 1, types.Top)
+	if !errors.Is(err, ErrInsufficientDepth) {
+		t.Fatalf("expected ErrInsufficientDepth against an empty side, got %v", err)
+	}
+	if filled != 0 {
+		t.Fatalf("expected filled 0 against an empty side, got %d", filled)
+	}
+}
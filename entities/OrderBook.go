@@ -3,38 +3,189 @@ package entities
 
 // Import standard and external packages.
 import (
-	"container/heap" // Provides heap operations for implementing priority queues.
-	"fmt"            // Package for formatted I/O.
-	"log"            // Package for logging errors and information.
-	"time"           // Package for time-related functions.
+	"fmt"  // Package for formatted I/O and error construction.
+	"sync" // Package providing the mutex guarding concurrent access to the book.
+	"time" // Package for time-related functions.
 
 	"github.com/google/uuid"                                  // Package to generate unique identifiers (UUIDs) for orders.
-	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Importing the utils package with alias 'utils' which contains definitions for LimitOrder and OrderPriorityQueue.
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Importing the utils package with alias 'utils' which contains definitions for LimitOrder and OrderLadder.
 	"github.com/shopspring/decimal"                           // Package for high-precision decimal arithmetic.
 )
 
 // OrderBook struct represents a market order book for a specific ticker.
 type OrderBook struct {
-	ticker string                   // The ticker symbol that this order book is associated with.
-	Bids   utils.OrderPriorityQueue // A priority queue (heap) that stores bid (buy) orders.
-	Asks   utils.OrderPriorityQueue // A priority queue (heap) that stores ask (sell) orders.
+	ticker string             // The ticker symbol that this order book is associated with.
+	Bids   *utils.OrderLadder // Price-level ladder that stores bid (buy) orders, best (highest) price first.
+	Asks   *utils.OrderLadder // Price-level ladder that stores ask (sell) orders, best price first.
+
+	mu          sync.Mutex      // Guards Bids, Asks, subscribers and breaker against concurrent access.
+	subscribers []chan Trade    // Channels registered via Subscribe to receive executed trades.
+	breaker     *CircuitBreaker // Risk control checked before an order is allowed to rest on the book, if installed.
+}
+
+// Trade records a single execution produced by the book's matching logic,
+// whether from continuous Match() or a call-auction MatchAuction().
+type Trade struct {
+	Ticker      string          // Ticker symbol the trade occurred on.
+	BuyOrderID  uuid.UUID       // ID of the bid order that participated in the trade.
+	SellOrderID uuid.UUID       // ID of the ask order that participated in the trade.
+	Price       decimal.Decimal // Price the trade executed at.
+	Quantity    int             // Number of shares matched.
+	Timestamp   time.Time       // Time the trade was executed.
 }
 
 // NewOrderBook returns a new instance of OrderBook for a given ticker.
 func NewOrderBook(ticker string) *OrderBook {
-	// Create a new OrderBook instance with empty bid and ask queues.
-	ob := &OrderBook{
-		ticker: ticker,                            // Set the ticker symbol for the order book.
-		Bids:   make(utils.OrderPriorityQueue, 0), // Initialize the bid queue with zero length.
-		Asks:   make(utils.OrderPriorityQueue, 0), // Initialize the ask queue with zero length.
+	// Create a new OrderBook instance with empty bid and ask ladders.
+	return &OrderBook{
+		ticker: ticker,                      // Set the ticker symbol for the order book.
+		Bids:   utils.NewOrderLadder(true),  // Initialize the bid ladder (best price is the highest).
+		Asks:   utils.NewOrderLadder(false), // Initialize the ask ladder (best price is the lowest).
+	}
+}
+
+// Ticker returns the ticker symbol this order book is associated with.
+func (ob *OrderBook) Ticker() string {
+	return ob.ticker
+}
+
+// BestPrice returns the best resting price on one side of the book, taking
+// ob.mu so that callers outside this package (such as twap.Execution) never
+// need to read Bids/Asks directly and race against Match or AddOrder. side
+// follows the same convention as DepthPrice: true for the asks (pricing a
+// buy), false for the bids (pricing a sell). It reports false if that side
+// is empty.
+func (ob *OrderBook) BestPrice(side bool) (decimal.Decimal, bool) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	var order *utils.LimitOrder
+	if side {
+		order = ob.Asks.Peek()
+	} else {
+		order = ob.Bids.Peek()
+	}
+	if order == nil {
+		return decimal.Decimal{}, false
+	}
+	return order.Price, true
+}
+
+// Subscribe registers ch to receive every Trade produced by this book until
+// the returned unsubscribe function is called. Publishing is non-blocking:
+// a subscriber that isn't keeping up misses trades rather than stalling the
+// matching loop.
+func (ob *OrderBook) Subscribe(ch chan Trade) (unsubscribe func()) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	ob.subscribers = append(ob.subscribers, ch)
+
+	return func() {
+		ob.mu.Lock()
+		defer ob.mu.Unlock()
+
+		for i, subscriber := range ob.subscribers {
+			if subscriber == ch {
+				ob.subscribers = append(ob.subscribers[:i], ob.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// publish fans trade out to every active subscriber. Callers must hold ob.mu.
+func (ob *OrderBook) publish(trade Trade) {
+	for _, subscriber := range ob.subscribers {
+		select {
+		case subscriber <- trade:
+		default: // Drop the trade for subscribers that are not keeping up.
+		}
+	}
+}
+
+// CancelOrder removes the resting order identified by id from the book, if
+// present, and reports whether it was found. Backed by OrderLadder's id
+// index, this is O(1), except when it empties a price level: dropping that
+// level from the sorted price index is O(P) in the number of levels.
+func (ob *OrderBook) CancelOrder(id uuid.UUID) bool {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	return ob.cancelLocked(id)
+}
+
+// cancelLocked removes the resting order identified by id, if present, and
+// reports whether it was found. Callers must hold ob.mu.
+func (ob *OrderBook) cancelLocked(id uuid.UUID) bool {
+	if ob.Bids.Remove(id) {
+		return true
 	}
+	return ob.Asks.Remove(id)
+}
+
+// ModifyOrder changes the price and/or quantity of the resting order
+// identified by id, reporting whether it was found. A quantity-only change
+// is applied in place and preserves the order's time priority; a price
+// change moves it to the back of the new price level's queue.
+func (ob *OrderBook) ModifyOrder(id uuid.UUID, newPrice decimal.Decimal, newQuantity int) bool {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
 
-	// Initialize the bid priority queue as a heap.
-	heap.Init(&ob.Bids)
-	// Initialize the ask priority queue as a heap.
-	heap.Init(&ob.Asks)
-	// Return the pointer to the newly created OrderBook.
-	return ob
+	if modifyInLadder(ob.Bids, id, newPrice, newQuantity) {
+		return true
+	}
+	return modifyInLadder(ob.Asks, id, newPrice, newQuantity)
+}
+
+// modifyInLadder applies a price/quantity change to the order identified by
+// id within ladder, if present. Callers must hold the book's lock.
+func modifyInLadder(ladder *utils.OrderLadder, id uuid.UUID, newPrice decimal.Decimal, newQuantity int) bool {
+	order, ok := ladder.Get(id)
+	if !ok {
+		return false
+	}
+
+	if order.Price.Equal(newPrice) {
+		ladder.SetQuantity(id, newQuantity)
+		return true
+	}
+
+	ladder.Remove(id)
+	order.Price = newPrice
+	order.Quantity = newQuantity
+	order.Timestamp = time.Now()
+	ladder.Push(order)
+	return true
+}
+
+// AddOrder inserts a fully constructed LimitOrder directly into the book,
+// bypassing price-string parsing. Execution algorithms (such as
+// twap.Execution) that need to know the resulting order's ID up front, in
+// order to track, repeg or cancel it later, should use this instead of
+// AddLimitOrder. If a CircuitBreaker is installed, it is checked before the
+// order is allowed to rest on the book.
+func (ob *OrderBook) AddOrder(order *utils.LimitOrder) (bool, error) {
+	if order.Ticker != ob.ticker {
+		return false, fmt.Errorf("entities: wrong ticker to place a new order: got %s, want %s", order.Ticker, ob.ticker)
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if ob.breaker != nil {
+		if err := ob.breaker.check(order); err != nil {
+			return false, err
+		}
+	}
+
+	if order.IsBid {
+		ob.Bids.Push(order)
+	} else {
+		ob.Asks.Push(order)
+	}
+
+	return true, nil
 }
 
 // intMin returns the smaller of two integer values.
@@ -47,21 +198,33 @@ func intMin(a int, b int) int {
 }
 
 // AddLimitOrder adds a new limit order to the order book.
-// It takes the ticker, price as a string, quantity, and a boolean indicating if it's a bid order.
-func (ob *OrderBook) AddLimitOrder(ticker string, priceString string, quantity int, IsBid bool) bool {
+// It takes the ticker, price as a string, quantity, and a boolean indicating
+// if it's a bid order. Rather than terminating the process, a malformed
+// ticker or price string, or a rejection from an installed CircuitBreaker,
+// is returned as an error so that bad input from an external feed cannot
+// take the book down.
+func (ob *OrderBook) AddLimitOrder(ticker string, priceString string, quantity int, IsBid bool) (bool, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if _, err := ob.addLimitOrderLocked(ticker, priceString, quantity, IsBid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// addLimitOrderLocked validates and inserts a new limit order, checking the
+// installed CircuitBreaker if any. Callers must hold ob.mu.
+func (ob *OrderBook) addLimitOrderLocked(ticker string, priceString string, quantity int, IsBid bool) (*utils.LimitOrder, error) {
 	// Validate that the provided ticker matches the order book's ticker.
 	if ticker != ob.ticker {
-		// Log a fatal error if the tickers do not match and terminate execution.
-		log.Fatalf("Wrong ticker to place a new order. Unable to create a new order: %s, %s, %d, IsBid: %t", ticker, priceString, quantity, IsBid)
-		return false // This return is unreachable due to log.Fatalf, but it's included to satisfy the function signature.
+		return nil, fmt.Errorf("entities: wrong ticker to place a new order: got %s, want %s", ticker, ob.ticker)
 	}
 
 	// Convert the price string to a decimal type for precise arithmetic.
 	priceDecimal, err := decimal.NewFromString(priceString)
-	// If an error occurs during conversion, log the error and exit.
 	if err != nil {
-		log.Fatalf("Invalid price found. Unable to create a new order: %s, %s, %d, IsBid: %t", ticker, priceString, quantity, IsBid)
-		return false // This return is unreachable because log.Fatalf exits, but it's provided as a safeguard.
+		return nil, fmt.Errorf("entities: invalid price %q: %w", priceString, err)
 	}
 
 	// Create a new LimitOrder struct with the provided values.
@@ -74,25 +237,33 @@ func (ob *OrderBook) AddLimitOrder(ticker string, priceString string, quantity i
 		Timestamp: time.Now(),   // Record the current time as the order's timestamp.
 	}
 
-	// Depending on whether the order is a bid or an ask, push it into the corresponding heap.
+	if ob.breaker != nil {
+		if err := ob.breaker.check(newOrder); err != nil {
+			return nil, err
+		}
+	}
+
+	// Depending on whether the order is a bid or an ask, push it into the corresponding ladder.
 	if newOrder.IsBid {
-		heap.Push(&ob.Bids, newOrder) // For bid orders, push onto the Bids heap.
+		ob.Bids.Push(newOrder) // For bid orders, push onto the Bids ladder.
 	} else {
-		heap.Push(&ob.Asks, newOrder) // For ask orders, push onto the Asks heap.
+		ob.Asks.Push(newOrder) // For ask orders, push onto the Asks ladder.
 	}
 
-	// Return true indicating the order was added successfully.
-	return true
+	return newOrder, nil
 }
 
 // Match processes the order matching within the order book.
 func (ob *OrderBook) Match() {
-	// Continue matching as long as there are orders in both the bid and ask queues.
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	// Continue matching as long as there are orders in both the bid and ask ladders.
 	for ob.Bids.Len() > 0 && ob.Asks.Len() > 0 {
-		// Peek at the highest priority bid order without removing it from the heap.
-		buy := ob.Bids.Peek().(*utils.LimitOrder)
-		// Peek at the highest priority ask order without removing it from the heap.
-		sell := ob.Asks.Peek().(*utils.LimitOrder)
+		// Peek at the highest priority bid order without removing it from the ladder.
+		buy := ob.Bids.Peek()
+		// Peek at the highest priority ask order without removing it from the ladder.
+		sell := ob.Asks.Peek()
 
 		// Print details of the bid and ask orders that are being considered for matching.
 		fmt.Printf("bid/ask to match: %d shares at %s VS %d shares at %s\n", buy.Quantity, buy.Price.String(), sell.Quantity, sell.Price.String())
@@ -103,29 +274,48 @@ func (ob *OrderBook) Match() {
 			break // Exit the loop since no orders can be matched under the current conditions.
 		}
 
-		// Remove the highest priority bid order from the heap.
-		buy = heap.Pop(&ob.Bids).(*utils.LimitOrder)
-		// Remove the highest priority ask order from the heap.
-		sell = heap.Pop(&ob.Asks).(*utils.LimitOrder)
+		// Remove the highest priority bid order from the ladder.
+		buy = ob.Bids.Pop()
+		// Remove the highest priority ask order from the ladder.
+		sell = ob.Asks.Pop()
 
 		// Determine the number of shares to be traded by finding the minimum of the two order quantities.
 		quantityFilled := intMin(buy.Quantity, sell.Quantity)
 		// Print the trade execution details: ticker, number of shares matched, and the trade price.
 		fmt.Printf("Ticker %s - Matched %d shares at %s\n", ob.ticker, quantityFilled, sell.Price.String())
 
+		trade := Trade{
+			Ticker:      ob.ticker,
+			BuyOrderID:  buy.ID,
+			SellOrderID: sell.ID,
+			Price:       sell.Price,
+			Quantity:    quantityFilled,
+			Timestamp:   time.Now(),
+		}
+
+		// Publish the execution to any subscribers (e.g. a twap.Execution tracking fills).
+		ob.publish(trade)
+
+		// Feed the trade into the circuit breaker's match history, if one is installed.
+		if ob.breaker != nil {
+			ob.breaker.recordTrade(trade)
+		}
+
 		// Deduct the matched quantity from the bid order.
 		buy.Quantity -= quantityFilled
 		// Deduct the matched quantity from the ask order.
 		sell.Quantity -= quantityFilled
 
-		// If the bid order still has remaining shares (i.e., partially filled), push it back onto the heap.
+		// If the bid order still has remaining shares (i.e., partially filled), restore it to the
+		// front of its price level so it keeps its original time priority.
 		if buy.Quantity > 0 {
-			heap.Push(&ob.Bids, buy)
+			ob.Bids.PushFront(buy)
 		}
 
-		// If the ask order still has remaining shares (i.e., partially filled), push it back onto the heap.
+		// If the ask order still has remaining shares (i.e., partially filled), restore it to the
+		// front of its price level so it keeps its original time priority.
 		if sell.Quantity > 0 {
-			heap.Push(&ob.Asks, sell)
+			ob.Asks.PushFront(sell)
 		}
 	}
 }
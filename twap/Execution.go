@@ -0,0 +1,324 @@
+// Package twap wraps entities.OrderBook to execute a large parent order as a
+// schedule of smaller child limit orders over time.
+package twap
+
+// Import standard and external packages.
+import (
+	"context" // Package for cancellation and deadline propagation.
+	"errors"  // Package for constructing sentinel errors.
+	"fmt"     // Package for formatted I/O and error construction.
+	"sync"    // Package providing the mutex guarding execution state.
+	"time"    // Package for time-related functions.
+
+	"github.com/google/uuid"                                  // Package to generate unique identifiers (UUIDs) for orders.
+	"github.com/manuelinfosec/limit-orderbook-go/entities"    // Package containing the OrderBook this execution trades against.
+	utils "github.com/manuelinfosec/limit-orderbook-go/utils" // Package containing the LimitOrder definition.
+	"github.com/shopspring/decimal"                           // Package for high-precision decimal arithmetic.
+)
+
+// ErrCancelled is returned by Run once GracefulCancel has stopped it.
+var ErrCancelled = errors.New("twap: execution was gracefully cancelled")
+
+// Params configures a single TWAP (time-weighted average price) execution.
+type Params struct {
+	Side                 bool            // true to execute on the bid (buy) side, false for the ask (sell) side.
+	TargetQuantity       int             // Total quantity to execute over the life of the schedule.
+	SliceQuantity        int             // Quantity to place per child order slice.
+	StopPrice            decimal.Decimal // Protective limit: the execution halts if the market trades through this price. Zero disables the check.
+	UpdateInterval       time.Duration   // How often a resting child order is re-pegged to the current best opposite price.
+	DelayInterval        time.Duration   // Delay between successive child order slices.
+	OrderUpdateRateLimit string          // Token-bucket spec capping repeg frequency, e.g. "1+1/1m".
+}
+
+// childOrder tracks one resting child order on Execution's side of the
+// book. remaining is Execution's own account of how much of order is still
+// unfilled; it is updated solely by handleTrade under e.mu and must never
+// be confused with order.Quantity, which belongs to the book and is
+// mutated by OrderBook.Match/MatchAuction under ob.mu. Reading or writing
+// order.Quantity from here would race the book and double-count fills,
+// since the same *utils.LimitOrder is the one resting in its ladder.
+type childOrder struct {
+	order     *utils.LimitOrder
+	remaining int
+}
+
+// Execution runs a TWAP schedule against a single entities.OrderBook. It
+// slices TargetQuantity into child orders of at most SliceQuantity, released
+// every DelayInterval, and repegs its resting child order to the best
+// opposite price every UpdateInterval until the target is filled, StopPrice
+// is breached, or the execution is cancelled.
+type Execution struct {
+	ob      *entities.OrderBook // Order book this execution trades against.
+	params  Params              // Execution parameters.
+	limiter *rateLimiter        // Caps how often resting orders are repegged.
+
+	mu     sync.Mutex                // Guards active and filled below.
+	active map[uuid.UUID]*childOrder // Child orders currently resting on the book, keyed by ID.
+	filled int                       // Total quantity filled so far.
+
+	trades chan entities.Trade // Subscription to the book's trade feed.
+	fills  chan entities.Trade // This execution's own fills, surfaced to callers.
+
+	cancelled  chan struct{} // Closed by GracefulCancel to stop Run from placing further slices.
+	cancelOnce sync.Once     // Guards against closing cancelled more than once.
+}
+
+// NewExecution constructs a TWAP execution against ob using params.
+func NewExecution(ob *entities.OrderBook, params Params) (*Execution, error) {
+	limiter, err := parseRateLimit(params.OrderUpdateRateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Execution{
+		ob:        ob,
+		params:    params,
+		limiter:   limiter,
+		active:    make(map[uuid.UUID]*childOrder),
+		trades:    make(chan entities.Trade, 256),
+		fills:     make(chan entities.Trade, 256),
+		cancelled: make(chan struct{}),
+	}, nil
+}
+
+// Fills returns the channel on which this execution's own fills are
+// published. It is closed once Run returns.
+func (e *Execution) Fills() <-chan entities.Trade {
+	return e.fills
+}
+
+// Run drives the execution until TargetQuantity is filled, StopPrice is
+// breached, or ctx is cancelled.
+func (e *Execution) Run(ctx context.Context) error {
+	unsubscribe := e.ob.Subscribe(e.trades)
+	defer unsubscribe()
+	defer close(e.fills)
+
+	sliceTicker := time.NewTicker(e.params.DelayInterval)
+	defer sliceTicker.Stop()
+
+	repegTicker := time.NewTicker(e.params.UpdateInterval)
+	defer repegTicker.Stop()
+
+	for {
+		e.mu.Lock()
+		done := e.filled >= e.params.TargetQuantity
+		e.mu.Unlock()
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			e.cancelActive()
+			return ctx.Err()
+
+		case <-e.cancelled:
+			e.cancelActive()
+			return ErrCancelled
+
+		case trade := <-e.trades:
+			e.handleTrade(trade)
+
+		case <-sliceTicker.C:
+			if err := e.placeSlice(); err != nil {
+				e.cancelActive()
+				return err
+			}
+
+		case <-repegTicker.C:
+			if err := e.repegActive(); err != nil {
+				e.cancelActive()
+				return err
+			}
+		}
+	}
+}
+
+// GracefulCancel stops Run from placing or repegging any further child
+// order slices and cancels every resting child order belonging to this
+// execution, leaving any quantity already filled in place.
+func (e *Execution) GracefulCancel(ctx context.Context) error {
+	e.cancelOnce.Do(func() { close(e.cancelled) })
+	e.cancelActive()
+	return ctx.Err()
+}
+
+// placeSlice submits the next child order slice, sized to the lesser of
+// SliceQuantity and the quantity still required to reach TargetQuantity.
+func (e *Execution) placeSlice() error {
+	select {
+	case <-e.cancelled:
+		return nil // GracefulCancel won the race with this tick; place nothing.
+	default:
+	}
+
+	e.mu.Lock()
+	remaining := e.params.TargetQuantity - e.filled - e.activeQuantityLocked()
+	e.mu.Unlock()
+
+	if remaining <= 0 {
+		return nil
+	}
+
+	quantity := e.params.SliceQuantity
+	if quantity > remaining {
+		quantity = remaining
+	}
+
+	price, ok := e.bestOppositePrice()
+	if !ok {
+		return nil // Nothing resting on the opposite side to peg against yet.
+	}
+
+	if e.breachesStop(price) {
+		return fmt.Errorf("twap: stop price %s breached at %s, halting execution", e.params.StopPrice.String(), price.String())
+	}
+
+	order := &utils.LimitOrder{
+		ID:        uuid.New(),
+		Ticker:    e.ob.Ticker(),
+		Price:     price,
+		Quantity:  quantity,
+		IsBid:     e.params.Side,
+		Timestamp: time.Now(),
+	}
+
+	if _, err := e.ob.AddOrder(order); err != nil {
+		return fmt.Errorf("twap: failed to place child order for %s: %w", e.ob.Ticker(), err)
+	}
+
+	e.mu.Lock()
+	e.active[order.ID] = &childOrder{order: order, remaining: quantity}
+	e.mu.Unlock()
+
+	return nil
+}
+
+// repegActive cancels and replaces any active child order whose price has
+// drifted from the current best opposite price, subject to
+// OrderUpdateRateLimit. It returns an error, repegging nothing, if the best
+// opposite price has breached StopPrice.
+func (e *Execution) repegActive() error {
+	price, ok := e.bestOppositePrice()
+	if !ok {
+		return nil
+	}
+
+	if e.breachesStop(price) {
+		return fmt.Errorf("twap: stop price %s breached at %s, halting execution", e.params.StopPrice.String(), price.String())
+	}
+
+	e.mu.Lock()
+	stale := make([]*childOrder, 0, len(e.active))
+	for _, child := range e.active {
+		if !child.order.Price.Equal(price) {
+			stale = append(stale, child)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, child := range stale {
+		if !e.limiter.Allow() {
+			return nil
+		}
+
+		e.ob.CancelOrder(child.order.ID)
+
+		e.mu.Lock()
+		delete(e.active, child.order.ID)
+		remaining := child.remaining
+		e.mu.Unlock()
+
+		repegged := &utils.LimitOrder{
+			ID:        uuid.New(),
+			Ticker:    e.ob.Ticker(),
+			Price:     price,
+			Quantity:  remaining,
+			IsBid:     e.params.Side,
+			Timestamp: time.Now(),
+		}
+
+		if _, err := e.ob.AddOrder(repegged); err == nil {
+			e.mu.Lock()
+			e.active[repegged.ID] = &childOrder{order: repegged, remaining: remaining}
+			e.mu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// handleTrade updates execution state when one of this execution's own
+// child orders participates in a trade, forwarding the fill to callers.
+func (e *Execution) handleTrade(trade entities.Trade) {
+	orderID := trade.SellOrderID
+	if e.params.Side {
+		orderID = trade.BuyOrderID
+	}
+
+	e.mu.Lock()
+	child, ok := e.active[orderID]
+	if ok {
+		child.remaining -= trade.Quantity
+		e.filled += trade.Quantity
+		if child.remaining <= 0 {
+			delete(e.active, orderID)
+		}
+	}
+	e.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case e.fills <- trade:
+	default: // Drop the fill if the caller isn't draining Fills() fast enough.
+	}
+}
+
+// cancelActive cancels every resting child order belonging to this execution.
+func (e *Execution) cancelActive() {
+	e.mu.Lock()
+	orders := make([]uuid.UUID, 0, len(e.active))
+	for id := range e.active {
+		orders = append(orders, id)
+	}
+	e.mu.Unlock()
+
+	for _, id := range orders {
+		e.ob.CancelOrder(id)
+		e.mu.Lock()
+		delete(e.active, id)
+		e.mu.Unlock()
+	}
+}
+
+// activeQuantityLocked sums the quantity still resting across active child
+// orders, per Execution's own remaining accounting. Callers must hold e.mu.
+func (e *Execution) activeQuantityLocked() int {
+	total := 0
+	for _, child := range e.active {
+		total += child.remaining
+	}
+	return total
+}
+
+// bestOppositePrice returns the best resting price on the side opposite the
+// execution (asks for a buy-side TWAP, bids for a sell-side one), via
+// OrderBook.BestPrice so it doesn't read Bids/Asks without the book's lock.
+func (e *Execution) bestOppositePrice() (decimal.Decimal, bool) {
+	return e.ob.BestPrice(e.params.Side)
+}
+
+// breachesStop reports whether price has moved through the protective stop.
+func (e *Execution) breachesStop(price decimal.Decimal) bool {
+	if e.params.StopPrice.IsZero() {
+		return false
+	}
+	if e.params.Side {
+		return price.GreaterThan(e.params.StopPrice)
+	}
+	return price.LessThan(e.params.StopPrice)
+}
@@ -0,0 +1,122 @@
+package twap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/manuelinfosec/limit-orderbook-go/entities"
+)
+
+// TestExecutionHandlesMultipleMatchesBeforeFillsDrained drives several
+// Match() calls against a single resting child order before anything reads
+// Fills(), the scenario that used to double-count fills because handleTrade
+// mutated the same *utils.LimitOrder the book was also mutating under its
+// own lock.
+func TestExecutionHandlesMultipleMatchesBeforeFillsDrained(t *testing.T) {
+	ob := entities.NewOrderBook("TEST")
+	for i := 0; i < 5; i++ {
+		if _, err := ob.AddLimitOrder("TEST", "10.00", 10, false); err != nil {
+			t.Fatalf("seed ask failed: %v", err)
+		}
+	}
+
+	exec, err := NewExecution(ob, Params{
+		Side:                 true,
+		TargetQuantity:       50,
+		SliceQuantity:        50,
+		UpdateInterval:       time.Hour,
+		DelayInterval:        5 * time.Millisecond,
+		OrderUpdateRateLimit: "1+1/1h",
+	})
+	if err != nil {
+		t.Fatalf("NewExecution failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- exec.Run(ctx) }()
+
+	// Poll via the locked BestPrice accessor rather than reading ob.Bids
+	// directly, which would itself race Push/Pop running under ob.mu.
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := ob.BestPrice(false); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the child order to be placed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 5; i++ {
+		ob.Match()
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the target quantity was fully filled")
+	}
+
+	var total, count int
+	for trade := range exec.Fills() {
+		total += trade.Quantity
+		count++
+	}
+
+	if total != 50 {
+		t.Fatalf("expected 50 shares filled across Fills(), got %d", total)
+	}
+	if count != 5 {
+		t.Fatalf("expected 5 separate fill events, got %d", count)
+	}
+}
+
+// TestExecutionGracefulCancelStopsRun exercises GracefulCancel: it should
+// stop Run from placing further slices rather than letting the next
+// sliceTicker tick resurrect the execution.
+func TestExecutionGracefulCancelStopsRun(t *testing.T) {
+	ob := entities.NewOrderBook("TEST")
+
+	exec, err := NewExecution(ob, Params{
+		Side:                 true,
+		TargetQuantity:       100,
+		SliceQuantity:        10,
+		UpdateInterval:       time.Hour,
+		DelayInterval:        5 * time.Millisecond,
+		OrderUpdateRateLimit: "1+1/1h",
+	})
+	if err != nil {
+		t.Fatalf("NewExecution failed: %v", err)
+	}
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() { done <- exec.Run(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := exec.GracefulCancel(context.Background()); err != nil {
+		t.Fatalf("GracefulCancel failed: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != ErrCancelled {
+			t.Fatalf("expected Run to return ErrCancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after GracefulCancel")
+	}
+
+	// No bids should remain resting once cancellation has settled.
+	if n := ob.Bids.Len(); n != 0 {
+		t.Fatalf("expected GracefulCancel to leave no resting bids, got %d", n)
+	}
+}
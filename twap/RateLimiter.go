@@ -0,0 +1,79 @@
+// Package twap wraps entities.OrderBook to execute a large parent order as a
+// schedule of smaller child limit orders over time.
+package twap
+
+// Import standard packages.
+import (
+	"fmt"     // Package for formatted I/O and error construction.
+	"strconv" // Package for conversions to and from string representations.
+	"strings" // Package for string manipulation.
+	"sync"    // Package providing the mutex guarding the token bucket.
+	"time"    // Package for time-related functions.
+)
+
+// rateLimiter is a simple token-bucket limiter used to cap how often an
+// Execution is allowed to cancel and repeg its resting child order.
+type rateLimiter struct {
+	mu         sync.Mutex    // Guards the fields below.
+	tokens     float64       // Tokens currently available.
+	burst      float64       // Maximum tokens the bucket can hold.
+	refill     float64       // Tokens added per period.
+	period     time.Duration // Duration over which refill tokens are added.
+	lastRefill time.Time     // Last time tokens were topped up.
+}
+
+// parseRateLimit parses a compact rate limit spec of the form
+// "<burst>+<refill>/<period>", e.g. "1+1/1m" allows one update immediately
+// and then refills by one token every minute.
+func parseRateLimit(spec string) (*rateLimiter, error) {
+	plusIdx := strings.Index(spec, "+")
+	slashIdx := strings.Index(spec, "/")
+	if plusIdx == -1 || slashIdx == -1 || slashIdx < plusIdx {
+		return nil, fmt.Errorf("twap: invalid rate limit spec %q, expected \"<burst>+<refill>/<period>\"", spec)
+	}
+
+	burst, err := strconv.ParseFloat(spec[:plusIdx], 64)
+	if err != nil {
+		return nil, fmt.Errorf("twap: invalid burst in rate limit spec %q: %w", spec, err)
+	}
+
+	refill, err := strconv.ParseFloat(spec[plusIdx+1:slashIdx], 64)
+	if err != nil {
+		return nil, fmt.Errorf("twap: invalid refill in rate limit spec %q: %w", spec, err)
+	}
+
+	period, err := time.ParseDuration(spec[slashIdx+1:])
+	if err != nil {
+		return nil, fmt.Errorf("twap: invalid period in rate limit spec %q: %w", spec, err)
+	}
+
+	return &rateLimiter{
+		tokens:     burst,
+		burst:      burst,
+		refill:     refill,
+		period:     period,
+		lastRefill: time.Now(),
+	}, nil
+}
+
+// Allow reports whether an update may proceed now, consuming a token if so.
+func (r *rateLimiter) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.period > 0 {
+		elapsed := time.Since(r.lastRefill)
+		r.tokens += elapsed.Seconds() / r.period.Seconds() * r.refill
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+		r.lastRefill = time.Now()
+	}
+
+	if r.tokens < 1 {
+		return false
+	}
+
+	r.tokens--
+	return true
+}
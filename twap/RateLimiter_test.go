@@ -0,0 +1,65 @@
+package twap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	r, err := parseRateLimit("2+1/1m")
+	if err != nil {
+		t.Fatalf("parseRateLimit failed: %v", err)
+	}
+	if r.burst != 2 || r.refill != 1 || r.period != time.Minute {
+		t.Fatalf("parsed rate limiter fields wrong: %+v", r)
+	}
+}
+
+func TestParseRateLimitInvalid(t *testing.T) {
+	cases := []string{"", "1/1m", "1+1", "x+1/1m", "1+x/1m", "1+1/xm"}
+	for _, spec := range cases {
+		if _, err := parseRateLimit(spec); err == nil {
+			t.Fatalf("expected parseRateLimit(%q) to fail", spec)
+		}
+	}
+}
+
+func TestRateLimiterBurstThenDepleted(t *testing.T) {
+	r, err := parseRateLimit("2+1/1h")
+	if err != nil {
+		t.Fatalf("parseRateLimit failed: %v", err)
+	}
+
+	if !r.Allow() {
+		t.Fatal("expected first Allow within burst to succeed")
+	}
+	if !r.Allow() {
+		t.Fatal("expected second Allow within burst to succeed")
+	}
+	if r.Allow() {
+		t.Fatal("expected Allow to fail once burst is exhausted")
+	}
+}
+
+func TestRateLimiterRefillsOverTime(t *testing.T) {
+	r, err := parseRateLimit("1+1/1h")
+	if err != nil {
+		t.Fatalf("parseRateLimit failed: %v", err)
+	}
+
+	if !r.Allow() {
+		t.Fatal("expected first Allow to succeed")
+	}
+	if r.Allow() {
+		t.Fatal("expected Allow to fail immediately after exhausting burst")
+	}
+
+	// Simulate the refill period having elapsed.
+	r.mu.Lock()
+	r.lastRefill = r.lastRefill.Add(-time.Hour)
+	r.mu.Unlock()
+
+	if !r.Allow() {
+		t.Fatal("expected Allow to succeed once a full period has elapsed")
+	}
+}
@@ -0,0 +1,32 @@
+// Package types contains small shared value types used across the order
+// book and its execution algorithms.
+package types
+
+// DepthKind enumerates the depth strategies supported by Depth.
+type DepthKind int
+
+// Supported depth strategies for pricing against the book.
+const (
+	TopOfBook DepthKind = iota // Price against only the best resting price.
+	NLevels                    // Price by walking up to a bounded number of distinct price levels.
+	FullDepth                  // Price by walking every resting order on the relevant side.
+)
+
+// Depth configures how far into the book a depth-aware price lookup, such
+// as entities.OrderBook.DepthPrice, should walk.
+type Depth struct {
+	Kind   DepthKind // Which depth strategy to apply.
+	Levels int       // Number of distinct price levels to walk when Kind is NLevels. Ignored otherwise.
+}
+
+// Top is a Depth that prices against only the best resting price.
+var Top = Depth{Kind: TopOfBook}
+
+// Full is a Depth that prices by walking the entire relevant side of the book.
+var Full = Depth{Kind: FullDepth}
+
+// WithLevels returns a Depth that prices by walking up to n distinct price
+// levels.
+func WithLevels(n int) Depth {
+	return Depth{Kind: NLevels, Levels: n}
+}
@@ -0,0 +1,35 @@
+// Package utils provides utility types and functions for the limit order book.
+package utils
+
+// Import necessary packages.
+import (
+	"fmt"  // For formatted I/O operations.
+	"time" // For working with time values.
+
+	"github.com/google/uuid"        // For generating unique identifiers for orders.
+	"github.com/shopspring/decimal" // For high-precision decimal arithmetic operations.
+)
+
+// LimitOrder represents an individual limit order in the order book.
+type LimitOrder struct {
+	ID        uuid.UUID       // Unique identifier for the order.
+	Ticker    string          // Stock or asset ticker symbol associated with the order.
+	Price     decimal.Decimal // Price at which the order is placed, using high-precision decimals.
+	Quantity  int             // Number of shares (or units) specified in the order.
+	IsBid     bool            // Boolean flag indicating if the order is a bid (buy) order; false implies an ask (sell) order.
+	Timestamp time.Time       // The time at which the order was created.
+}
+
+// String implements the Stringer interface for LimitOrder.
+// It returns a formatted string representation of the LimitOrder.
+func (lo LimitOrder) String() string {
+	// Format the LimitOrder details into a string.
+	// Format: [Ticker - Price - Quantity - Is Buy: IsBid - Timestamp in milliseconds - Order ID]
+	return fmt.Sprintf("[%s - %s - %d - Is Buy: %t - %d - %s]\n",
+		lo.Ticker,                // Ticker symbol.
+		lo.Price.String(),        // Price formatted as a string.
+		lo.Quantity,              // Order quantity.
+		lo.IsBid,                 // Boolean indicating if the order is a bid.
+		lo.Timestamp.UnixMilli(), // Timestamp converted to milliseconds.
+		lo.ID.String())           // Unique order ID as a string.
+}
@@ -0,0 +1,293 @@
+// Package utils provides utility types and functions for the limit order book.
+package utils
+
+// Import necessary packages.
+import (
+	"sort" // For maintaining the sorted index of active price levels.
+
+	"github.com/google/uuid"        // For generating unique identifiers for orders.
+	"github.com/shopspring/decimal" // For high-precision decimal arithmetic operations.
+)
+
+// orderNode is one entry in a priceLevel's FIFO queue. It also doubles as
+// the value stored in OrderLadder's id index, which is what lets
+// OrderLadder.Remove and OrderLadder.Get locate a resting order in O(1)
+// instead of scanning every level.
+type orderNode struct {
+	order *LimitOrder
+	prev  *orderNode
+	next  *orderNode
+	level *priceLevel
+}
+
+// priceLevel is the FIFO queue of orders resting at a single price,
+// preserving time priority within the level.
+type priceLevel struct {
+	price    decimal.Decimal
+	quantity int
+	head     *orderNode
+	tail     *orderNode
+	size     int
+}
+
+// pushBack appends order to the tail of the level's FIFO queue.
+func (pl *priceLevel) pushBack(order *LimitOrder) *orderNode {
+	node := &orderNode{order: order, level: pl}
+
+	if pl.tail == nil {
+		pl.head, pl.tail = node, node
+	} else {
+		node.prev = pl.tail
+		pl.tail.next = node
+		pl.tail = node
+	}
+
+	pl.size++
+	pl.quantity += order.Quantity
+	return node
+}
+
+// pushFront inserts order at the head of the level's FIFO queue. Used to
+// restore a partially filled order's time priority after a match.
+func (pl *priceLevel) pushFront(order *LimitOrder) *orderNode {
+	node := &orderNode{order: order, level: pl}
+
+	if pl.head == nil {
+		pl.head, pl.tail = node, node
+	} else {
+		node.next = pl.head
+		pl.head.prev = node
+		pl.head = node
+	}
+
+	pl.size++
+	pl.quantity += order.Quantity
+	return node
+}
+
+// remove detaches node from its level's FIFO queue in O(1).
+func (pl *priceLevel) remove(node *orderNode) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		pl.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		pl.tail = node.prev
+	}
+
+	pl.size--
+	pl.quantity -= node.order.Quantity
+}
+
+// OrderLadder is a two-tier order book side: a map from price to priceLevel
+// for O(1) level access, a sorted index of active prices for O(log P)
+// best-price and price lookup (via sort.Search), and an id index for O(1)
+// cancel/modify. Within a level, orders are held in a FIFO linked list that
+// preserves time priority.
+//
+// This replaces the previous container/heap-based OrderPriorityQueue, which
+// had no way to cancel or modify a resting order without an O(N) scan.
+//
+// The price index itself is a plain sorted slice, not a balanced tree or
+// skiplist: inserting or removing a level is an O(log P) search followed by
+// an O(P) shift of the slice, where P is the number of distinct active
+// price levels. That is fine for the price ranges this book trades over in
+// practice, but it is not the O(log P) insert/remove a tree or skiplist
+// would guarantee as P grows large.
+type OrderLadder struct {
+	IsBid  bool // true for a bid ladder (best price is the highest), false for an ask ladder (best price is the lowest).
+	levels map[string]*priceLevel
+	index  []decimal.Decimal // Active price levels, kept sorted ascending.
+	nodes  map[uuid.UUID]*orderNode
+	qty    int // Total resting quantity across all levels.
+}
+
+// NewOrderLadder returns an empty OrderLadder for the given side.
+func NewOrderLadder(isBid bool) *OrderLadder {
+	return &OrderLadder{
+		IsBid:  isBid,
+		levels: make(map[string]*priceLevel),
+		nodes:  make(map[uuid.UUID]*orderNode),
+	}
+}
+
+// Len returns the total number of resting orders across all price levels.
+func (ol *OrderLadder) Len() int {
+	return len(ol.nodes)
+}
+
+// Quantity returns the total resting quantity across all price levels.
+func (ol *OrderLadder) Quantity() int {
+	return ol.qty
+}
+
+// bestIndex returns the index into ol.index of the best price for this
+// side: the last (highest) entry for a bid ladder, the first (lowest) for
+// an ask ladder.
+func (ol *OrderLadder) bestIndex() int {
+	if ol.IsBid {
+		return len(ol.index) - 1
+	}
+	return 0
+}
+
+// levelAt fetches or, if needed, creates the priceLevel for price,
+// inserting it into the sorted index.
+func (ol *OrderLadder) levelAt(price decimal.Decimal) *priceLevel {
+	key := price.String()
+
+	level, ok := ol.levels[key]
+	if ok {
+		return level
+	}
+
+	level = &priceLevel{price: price}
+	ol.levels[key] = level
+	ol.insertLevel(price)
+	return level
+}
+
+// insertLevel adds price to the sorted index, keeping it ascending. The
+// binary search is O(log P), but the append/copy that makes room for it is
+// O(P).
+func (ol *OrderLadder) insertLevel(price decimal.Decimal) {
+	i := sort.Search(len(ol.index), func(i int) bool { return !ol.index[i].LessThan(price) })
+	ol.index = append(ol.index, decimal.Decimal{})
+	copy(ol.index[i+1:], ol.index[i:])
+	ol.index[i] = price
+}
+
+// removeLevel deletes price from the sorted index. As with insertLevel, the
+// search is O(log P) but the resulting shift is O(P).
+func (ol *OrderLadder) removeLevel(price decimal.Decimal) {
+	i := sort.Search(len(ol.index), func(i int) bool { return !ol.index[i].LessThan(price) })
+	if i < len(ol.index) && ol.index[i].Equal(price) {
+		ol.index = append(ol.index[:i], ol.index[i+1:]...)
+	}
+}
+
+// Push inserts order into the ladder, creating its price level if needed,
+// at the back of that level's time-priority queue.
+func (ol *OrderLadder) Push(order *LimitOrder) {
+	level := ol.levelAt(order.Price)
+	ol.nodes[order.ID] = level.pushBack(order)
+	ol.qty += order.Quantity
+}
+
+// PushFront re-inserts order at the front of its price level's time
+// priority queue. Used to restore a partially filled order's priority
+// after a match, rather than sending it to the back as a fresh order would.
+func (ol *OrderLadder) PushFront(order *LimitOrder) {
+	level := ol.levelAt(order.Price)
+	ol.nodes[order.ID] = level.pushFront(order)
+	ol.qty += order.Quantity
+}
+
+// Peek returns the oldest order resting at the best price, or nil if the
+// ladder is empty.
+func (ol *OrderLadder) Peek() *LimitOrder {
+	if len(ol.index) == 0 {
+		return nil
+	}
+
+	level := ol.levels[ol.index[ol.bestIndex()].String()]
+	if level == nil || level.head == nil {
+		return nil
+	}
+	return level.head.order
+}
+
+// Pop removes and returns the oldest order resting at the best price, or
+// nil if the ladder is empty.
+func (ol *OrderLadder) Pop() *LimitOrder {
+	if len(ol.index) == 0 {
+		return nil
+	}
+
+	price := ol.index[ol.bestIndex()]
+	level := ol.levels[price.String()]
+	if level == nil || level.head == nil {
+		return nil
+	}
+
+	node := level.head
+	ol.removeNode(node)
+	return node.order
+}
+
+// Remove cancels the resting order identified by id in O(1), reporting
+// whether it was found. If that empties its price level, removing the level
+// from the sorted index costs O(P), as described on OrderLadder.
+func (ol *OrderLadder) Remove(id uuid.UUID) bool {
+	node, ok := ol.nodes[id]
+	if !ok {
+		return false
+	}
+
+	ol.removeNode(node)
+	return true
+}
+
+// removeNode detaches node from its level, tidying up an emptied level and
+// the id index.
+func (ol *OrderLadder) removeNode(node *orderNode) {
+	level := node.level
+	ol.qty -= node.order.Quantity
+	level.remove(node)
+	delete(ol.nodes, node.order.ID)
+
+	if level.size == 0 {
+		delete(ol.levels, level.price.String())
+		ol.removeLevel(level.price)
+	}
+}
+
+// Get returns the resting order with the given id, if present.
+func (ol *OrderLadder) Get(id uuid.UUID) (*LimitOrder, bool) {
+	node, ok := ol.nodes[id]
+	if !ok {
+		return nil, false
+	}
+	return node.order, true
+}
+
+// SetQuantity updates the quantity of the resting order identified by id in
+// place, preserving its time priority, and reports whether it was found.
+func (ol *OrderLadder) SetQuantity(id uuid.UUID, quantity int) bool {
+	node, ok := ol.nodes[id]
+	if !ok {
+		return false
+	}
+
+	delta := quantity - node.order.Quantity
+	node.order.Quantity = quantity
+	node.level.quantity += delta
+	ol.qty += delta
+	return true
+}
+
+// Orders returns every resting order across all levels, best price first,
+// each level in FIFO time priority order. It allocates a fresh slice per
+// call and is intended for read-only traversal, e.g. by MatchAuction or
+// DepthPrice.
+func (ol *OrderLadder) Orders() []*LimitOrder {
+	orders := make([]*LimitOrder, 0, ol.Len())
+
+	start, step := 0, 1
+	if ol.IsBid {
+		start, step = len(ol.index)-1, -1
+	}
+
+	for i := start; i >= 0 && i < len(ol.index); i += step {
+		level := ol.levels[ol.index[i].String()]
+		for node := level.head; node != nil; node = node.next {
+			orders = append(orders, node.order)
+		}
+	}
+
+	return orders
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func newTestOrder(price string, quantity int) *LimitOrder {
+	return &LimitOrder{
+		ID:       uuid.New(),
+		Ticker:   "TEST",
+		Price:    decimal.RequireFromString(price),
+		Quantity: quantity,
+	}
+}
+
+func TestOrderLadderBestPriceOrdering(t *testing.T) {
+	bids := NewOrderLadder(true)
+	bids.Push(newTestOrder("10.00", 1))
+	bids.Push(newTestOrder("12.00", 1))
+	bids.Push(newTestOrder("11.00", 1))
+
+	if best := bids.Peek(); best == nil || !best.Price.Equal(decimal.RequireFromString("12.00")) {
+		t.Fatalf("expected best bid price 12.00, got %v", best)
+	}
+
+	asks := NewOrderLadder(false)
+	asks.Push(newTestOrder("10.00", 1))
+	asks.Push(newTestOrder("8.00", 1))
+	asks.Push(newTestOrder("9.00", 1))
+
+	if best := asks.Peek(); best == nil || !best.Price.Equal(decimal.RequireFromString("8.00")) {
+		t.Fatalf("expected best ask price 8.00, got %v", best)
+	}
+}
+
+func TestOrderLadderFIFOWithinLevel(t *testing.T) {
+	ladder := NewOrderLadder(true)
+	first := newTestOrder("10.00", 1)
+	second := newTestOrder("10.00", 1)
+	ladder.Push(first)
+	ladder.Push(second)
+
+	if got := ladder.Pop(); got.ID != first.ID {
+		t.Fatalf("expected FIFO order to pop %s first, got %s", first.ID, got.ID)
+	}
+	if got := ladder.Pop(); got.ID != second.ID {
+		t.Fatalf("expected FIFO order to pop %s second, got %s", second.ID, got.ID)
+	}
+}
+
+func TestOrderLadderRemove(t *testing.T) {
+	ladder := NewOrderLadder(true)
+	order := newTestOrder("10.00", 5)
+	ladder.Push(order)
+
+	if !ladder.Remove(order.ID) {
+		t.Fatal("expected Remove to find the order")
+	}
+	if ladder.Len() != 0 {
+		t.Fatalf("expected ladder to be empty after Remove, got Len()=%d", ladder.Len())
+	}
+	if ladder.Peek() != nil {
+		t.Fatal("expected empty ladder after removing its only order")
+	}
+	if ladder.Remove(order.ID) {
+		t.Fatal("expected Remove to report false for an order already removed")
+	}
+}
+
+func TestOrderLadderRemoveEmptiesLevelButKeepsOthers(t *testing.T) {
+	ladder := NewOrderLadder(false)
+	low := newTestOrder("8.00", 1)
+	high := newTestOrder("9.00", 1)
+	ladder.Push(low)
+	ladder.Push(high)
+
+	if !ladder.Remove(low.ID) {
+		t.Fatal("expected Remove to find low")
+	}
+	if best := ladder.Peek(); best == nil || best.ID != high.ID {
+		t.Fatalf("expected remaining best price to be %s, got %v", high.ID, best)
+	}
+}
+
+func TestOrderLadderQuantity(t *testing.T) {
+	ladder := NewOrderLadder(true)
+	ladder.Push(newTestOrder("10.00", 3))
+	ladder.Push(newTestOrder("11.00", 4))
+
+	if ladder.Quantity() != 7 {
+		t.Fatalf("expected total quantity 7, got %d", ladder.Quantity())
+	}
+
+	popped := ladder.Pop()
+	if ladder.Quantity() != 7-popped.Quantity {
+		t.Fatalf("expected quantity to drop by %d after Pop, got %d", popped.Quantity, ladder.Quantity())
+	}
+}
+
+func TestOrderLadderOrdersBestFirst(t *testing.T) {
+	bids := NewOrderLadder(true)
+	bids.Push(newTestOrder("10.00", 1))
+	bids.Push(newTestOrder("12.00", 1))
+	bids.Push(newTestOrder("11.00", 1))
+
+	orders := bids.Orders()
+	if len(orders) != 3 {
+		t.Fatalf("expected 3 orders, got %d", len(orders))
+	}
+	wantPrices := []string{"12.00", "11.00", "10.00"}
+	for i, want := range wantPrices {
+		if !orders[i].Price.Equal(decimal.RequireFromString(want)) {
+			t.Fatalf("expected Orders()[%d] price %s, got %s", i, want, orders[i].Price)
+		}
+	}
+}